@@ -0,0 +1,71 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+// PlaybackConfig describes how to open the output stream for the play
+// command.
+type PlaybackConfig struct {
+	Device     int // -1 selects the system default output device
+	SampleRate float64
+	Channels   int
+	Latency    string // "low" or "high"
+}
+
+// ParseLatency validates the --latency flag value.
+func ParseLatency(s string) (string, error) {
+	switch s {
+	case "low", "high":
+		return s, nil
+	default:
+		return "", fmt.Errorf("invalid latency mode: %s (want low or high)", s)
+	}
+}
+
+// outputDeviceByID looks up a device the same way listDevices enumerates
+// them, by index into portaudio.Devices().
+func outputDeviceByID(id int) (*portaudio.DeviceInfo, error) {
+	devices, err := portaudio.Devices()
+	if err != nil {
+		return nil, err
+	}
+	if id < 0 || id >= len(devices) {
+		return nil, fmt.Errorf("device id %d out of range (see 'list')", id)
+	}
+	return devices[id], nil
+}
+
+// openOutputStream opens the output stream described by cfg and wires
+// callback in as its sample source. When cfg requests a non-default device
+// or low latency, it resolves StreamParameters via portaudio.OpenStream;
+// otherwise it falls back to the simpler portaudio.OpenDefaultStream.
+func openOutputStream(cfg PlaybackConfig, callback func(out []float32)) (*portaudio.Stream, error) {
+	if cfg.Device < 0 && cfg.Latency != "low" {
+		return portaudio.OpenDefaultStream(0, cfg.Channels, cfg.SampleRate, 0, callback)
+	}
+
+	outDev, err := portaudio.DefaultOutputDevice()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Device >= 0 {
+		outDev, err = outputDeviceByID(cfg.Device)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var params portaudio.StreamParameters
+	if cfg.Latency == "low" {
+		params = portaudio.LowLatencyParameters(nil, outDev)
+	} else {
+		params = portaudio.HighLatencyParameters(nil, outDev)
+	}
+	params.Output.Channels = cfg.Channels
+	params.SampleRate = cfg.SampleRate
+
+	return portaudio.OpenStream(params, callback)
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Config is the on-disk structure of ~/.config/fuzzpillow/config.toml.
+type Config struct {
+	Preset map[string]Preset `toml:"preset"`
+}
+
+// Preset stores a named, reusable combination of play parameters. Only
+// Type and Duration are currently applied by 'play --preset'.
+type Preset struct {
+	Type     string `toml:"type"`
+	Duration string `toml:"duration"`
+}
+
+const defaultConfig = `# fuzzpillow presets: named combinations of play parameters.
+# Add a [preset.<name>] block and select it with 'play --preset <name>'.
+# Only type and duration are applied today.
+
+[preset.sleep]
+type = "brown"
+duration = "8h"
+
+[preset.focus]
+type = "pink"
+`
+
+// configPath returns the per-user config file path, following the XDG
+// base directory layout.
+func configPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "fuzzpillow", "config.toml"), nil
+}
+
+// loadConfig reads the config file, writing it with the default presets on
+// first run if it doesn't exist yet.
+func loadConfig() (*Config, error) {
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("could not create config directory: %w", err)
+		}
+		if err := os.WriteFile(path, []byte(defaultConfig), 0o644); err != nil {
+			return nil, fmt.Errorf("could not write default config: %w", err)
+		}
+	}
+
+	var cfg Config
+	if _, err := toml.DecodeFile(path, &cfg); err != nil {
+		return nil, fmt.Errorf("could not parse config at %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// lookupPreset finds a preset by name.
+func lookupPreset(cfg *Config, name string) (Preset, error) {
+	preset, ok := cfg.Preset[name]
+	if !ok {
+		return Preset{}, fmt.Errorf("unknown preset: %s (see 'presets')", name)
+	}
+	return preset, nil
+}
+
+// listPresets prints every configured preset and its noise type.
+func listPresets() {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if len(cfg.Preset) == 0 {
+		fmt.Println("No presets configured.")
+		return
+	}
+	for name, preset := range cfg.Preset {
+		fmt.Printf("%s: type=%s duration=%s\n", name, preset.Type, preset.Duration)
+	}
+}
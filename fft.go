@@ -0,0 +1,52 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// fft computes the discrete Fourier transform of in using a recursive
+// radix-2 Cooley-Tukey algorithm. len(in) must be a power of two.
+func fft(in []complex128) []complex128 {
+	n := len(in)
+	if n <= 1 {
+		return in
+	}
+
+	even := make([]complex128, n/2)
+	odd := make([]complex128, n/2)
+	for i := 0; i < n/2; i++ {
+		even[i] = in[2*i]
+		odd[i] = in[2*i+1]
+	}
+
+	y0 := fft(even)
+	y1 := fft(odd)
+
+	result := make([]complex128, n)
+	for k := 0; k < n/2; k++ {
+		w := cmplx.Exp(complex(0, -2*math.Pi*float64(k)/float64(n))) * y1[k]
+		result[k] = y0[k] + w
+		result[k+n/2] = y0[k] - w
+	}
+	return result
+}
+
+// nextPowerOfTwo returns the smallest power of two greater than or equal to n.
+func nextPowerOfTwo(n int) int {
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// hannWindow returns a Hann window of length n, used to taper an FFT input
+// frame so the frame boundaries don't leak energy across the spectrum.
+func hannWindow(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 * (1 - math.Cos(2*math.Pi*float64(i)/float64(n-1)))
+	}
+	return w
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+)
+
+func TestFFTOfConstantSignalIsDC(t *testing.T) {
+	in := make([]complex128, 8)
+	for i := range in {
+		in[i] = complex(1, 0)
+	}
+
+	out := fft(in)
+
+	if got, want := cmplx.Abs(out[0]), float64(len(in)); math.Abs(got-want) > 1e-9 {
+		t.Errorf("DC bin = %v, want %v", got, want)
+	}
+	for k := 1; k < len(out); k++ {
+		if got := cmplx.Abs(out[k]); got > 1e-9 {
+			t.Errorf("bin %d = %v, want ~0", k, got)
+		}
+	}
+}
+
+func TestNextPowerOfTwo(t *testing.T) {
+	cases := map[int]int{0: 1, 1: 1, 2: 2, 3: 4, 1024: 1024, 1025: 2048}
+	for n, want := range cases {
+		if got := nextPowerOfTwo(n); got != want {
+			t.Errorf("nextPowerOfTwo(%d) = %d, want %d", n, got, want)
+		}
+	}
+}
+
+func TestHannWindowEndpointsAreZero(t *testing.T) {
+	w := hannWindow(16)
+	if w[0] != 0 {
+		t.Errorf("w[0] = %v, want 0", w[0])
+	}
+	if last := w[len(w)-1]; last != 0 {
+		t.Errorf("w[last] = %v, want 0", last)
+	}
+}
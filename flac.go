@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/mewkiz/flac"
+	"github.com/mewkiz/flac/frame"
+	"github.com/mewkiz/flac/meta"
+)
+
+// flacBlockSize is the number of samples per encoded FLAC frame.
+const flacBlockSize = 4096
+
+// writeFLAC encodes mono samples as bits-per-sample FLAC frames using
+// verbatim subframes, which store raw PCM rather than running a predictor.
+// That trades compression ratio for a small, fast encoder, which is fine
+// for a noise generator's output. FLAC is integer PCM only, so bits must be
+// 16 or 24 (32f has no FLAC equivalent).
+func writeFLAC(w io.Writer, samples []float32, sampleRate, bits int) error {
+	if bits != 16 && bits != 24 {
+		return fmt.Errorf("flac supports bit depths 16 or 24, got %d", bits)
+	}
+	maxAmplitude := float64(int64(1)<<(bits-1) - 1)
+
+	info := &meta.StreamInfo{
+		BlockSizeMin:  flacBlockSize,
+		BlockSizeMax:  flacBlockSize,
+		SampleRate:    uint32(sampleRate),
+		NChannels:     1,
+		BitsPerSample: uint8(bits),
+		NSamples:      uint64(len(samples)),
+	}
+
+	enc, err := flac.NewEncoder(w, info)
+	if err != nil {
+		return fmt.Errorf("could not create flac encoder: %w", err)
+	}
+	defer enc.Close()
+
+	for start := 0; start < len(samples); start += flacBlockSize {
+		end := start + flacBlockSize
+		if end > len(samples) {
+			end = len(samples)
+		}
+		block := samples[start:end]
+
+		pcm := make([]int32, len(block))
+		for i, s := range block {
+			pcm[i] = int32(clampUnit(s) * maxAmplitude)
+		}
+
+		f := &frame.Frame{
+			Header: frame.Header{
+				BlockSize:     uint16(len(block)),
+				SampleRate:    uint32(sampleRate),
+				BitsPerSample: uint8(bits),
+				Channels:      frame.ChannelsMono,
+			},
+			Subframes: []*frame.Subframe{
+				{
+					SubHeader: frame.SubHeader{Pred: frame.PredVerbatim},
+					Samples:   pcm,
+				},
+			},
+		}
+
+		if err := enc.WriteFrame(f); err != nil {
+			return fmt.Errorf("could not write flac frame: %w", err)
+		}
+	}
+
+	return nil
+}
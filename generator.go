@@ -0,0 +1,43 @@
+package main
+
+import "fmt"
+
+// ParsePinkRows validates the --pink-rows flag value.
+func ParsePinkRows(n int) (int, error) {
+	if n < 1 {
+		return 0, fmt.Errorf("invalid pink-rows: %d (want a positive integer)", n)
+	}
+	return n, nil
+}
+
+// Generator produces audio samples for a single noise algorithm. Implementations
+// are stateful: Fill is called repeatedly from the PortAudio callback and must
+// pick up where the previous call left off, while Reset clears that state so
+// playback can restart cleanly (e.g. when the noise type changes).
+type Generator interface {
+	// Fill writes len(out) samples into out, advancing internal state.
+	Fill(out []float32, sampleRate float64)
+	// Reset clears any internal state accumulated by Fill.
+	Reset()
+}
+
+// NoiseOptions carries the CLI-tunable parameters for generators whose
+// algorithm has configurable coefficients (currently pink and brown noise).
+type NoiseOptions struct {
+	PinkRows  int
+	BrownLeak float64
+}
+
+// newGenerator returns a fresh Generator for the given NoiseType.
+func newGenerator(t NoiseType, opts NoiseOptions) Generator {
+	switch t {
+	case Brown:
+		return NewBrownNoiseGenerator(opts.BrownLeak)
+	case Pink:
+		return NewPinkNoiseGenerator(opts.PinkRows)
+	case Minor:
+		return NewMinorNoiseGenerator()
+	default:
+		return NewWhiteNoiseGenerator()
+	}
+}
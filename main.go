@@ -3,7 +3,6 @@ package main
 import (
 	"flag"
 	"fmt"
-	"math"
 	"math/rand"
 	"os"
 	"os/signal"
@@ -19,6 +18,9 @@ const (
 	noteLength   = 2   // length of each note in seconds
 	vibratoRate  = 5   // The speed of the vibrato effect, in oscillations per second
 	vibratoDepth = 0.1 // The depth (i.e., the amount of pitch variation) of the vibrato effect
+
+	defaultPinkRows  = 16   // number of Voss-McCartney rows for pink noise
+	defaultBrownLeak = 0.01 // leak coefficient for the brown noise integrator
 )
 
 // NoiseType represents different types of noise.
@@ -65,49 +67,111 @@ func main() {
 	playCommand := flag.NewFlagSet("play", flag.ExitOnError)
 	duration := playCommand.String("duration", "", "Duration to play the noise (default: until interrupted, format: 10s, 2m, 3h)")
 	noiseType := playCommand.String("type", "white", "Type of noise to play (options: white, brown, pink)")
+	pinkRows := playCommand.Int("pink-rows", defaultPinkRows, "Number of Voss-McCartney rows used to generate pink noise")
+	brownLeak := playCommand.Float64("brown-leak", defaultBrownLeak, "Leak coefficient of the brown noise integrator (0-1, higher pulls it back toward zero faster)")
+	device := playCommand.Int("device", -1, "Output device ID to play through (see 'list'); default is the system default device")
+	playSampleRate := playCommand.Float64("sample-rate", sampleRate, "Sample rate in Hz")
+	channels := playCommand.Int("channels", 1, "Number of output channels")
+	latency := playCommand.String("latency", "high", "Stream latency mode (options: low, high)")
+	preset := playCommand.String("preset", "", "Name of a configured preset to load play parameters from (see 'presets')")
+
+	renderCommand := flag.NewFlagSet("render", flag.ExitOnError)
+	renderOut := renderCommand.String("out", "", "Output file path, e.g. noise.wav")
+	renderFormat := renderCommand.String("format", "wav", "Output file format (options: wav, flac)")
+	renderDuration := renderCommand.String("duration", "10m", "Duration to render (format: 10s, 2m, 3h)")
+	renderType := renderCommand.String("type", "white", "Type of noise to render (options: white, brown, pink, minor)")
+	renderSampleRate := renderCommand.Float64("sample-rate", sampleRate, "Sample rate in Hz")
+	renderBitDepth := renderCommand.String("bit-depth", "16", "Bit depth (options: 16, 24, 32f)")
+	renderPinkRows := renderCommand.Int("pink-rows", defaultPinkRows, "Number of Voss-McCartney rows used to generate pink noise")
+	renderBrownLeak := renderCommand.Float64("brown-leak", defaultBrownLeak, "Leak coefficient of the brown noise integrator (0-1, higher pulls it back toward zero faster)")
 
 	if len(os.Args) < 2 {
-		fmt.Println("Expected 'play' or 'list' commands")
+		fmt.Println("Expected 'play', 'list', 'visualize', 'render', or 'presets' commands")
 		os.Exit(1)
 	}
 
 	switch os.Args[1] {
 	case "play":
 		playCommand.Parse(os.Args[2:])
+		if *preset != "" {
+			cfg, err := loadConfig()
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			p, err := lookupPreset(cfg, *preset)
+			if err != nil {
+				fmt.Println(err)
+				os.Exit(1)
+			}
+			*noiseType = p.Type
+			if p.Duration != "" {
+				*duration = p.Duration
+			}
+		}
 		parsedNoiseType, err := ParseNoiseType(*noiseType)
 		if err != nil {
 			fmt.Println(err)
 			os.Exit(1)
 		}
-		playNoise(*duration, parsedNoiseType)
+		parsedLatency, err := ParseLatency(*latency)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		parsedPinkRows, err := ParsePinkRows(*pinkRows)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		playNoise(*duration, parsedNoiseType,
+			NoiseOptions{PinkRows: parsedPinkRows, BrownLeak: *brownLeak},
+			PlaybackConfig{Device: *device, SampleRate: *playSampleRate, Channels: *channels, Latency: parsedLatency})
 	case "list":
 		listDevices()
+	case "visualize":
+		visualizeSpectrum()
+	case "presets":
+		listPresets()
+	case "render":
+		renderCommand.Parse(os.Args[2:])
+		if *renderOut == "" {
+			fmt.Println("render: --out is required")
+			os.Exit(1)
+		}
+		parsedNoiseType, err := ParseNoiseType(*renderType)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		parsedRenderPinkRows, err := ParsePinkRows(*renderPinkRows)
+		if err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
+		noiseOpts := NoiseOptions{PinkRows: parsedRenderPinkRows, BrownLeak: *renderBrownLeak}
+		if err := renderToFile(*renderOut, *renderFormat, *renderDuration, parsedNoiseType, noiseOpts, *renderSampleRate, *renderBitDepth); err != nil {
+			fmt.Println(err)
+			os.Exit(1)
+		}
 	case "help":
 		printHelp()
 	default:
-		fmt.Println("Expected 'play' or 'list' commands")
+		fmt.Println("Expected 'play', 'list', 'visualize', 'render', or 'presets' commands")
 		os.Exit(1)
 	}
 }
 
-func playNoise(duration string, noiseType NoiseType) {
+func playNoise(duration string, noiseType NoiseType, noiseOpts NoiseOptions, cfg PlaybackConfig) {
 	signalChannel := make(chan os.Signal, 1)
 	signal.Notify(signalChannel, syscall.SIGINT, syscall.SIGTERM)
 
-	numSamples := sampleRate * 60 // one minute of noise
-	samples := make([]float32, numSamples)
 	rand.Seed(time.Now().UnixNano())
+	generator := newGenerator(noiseType, noiseOpts)
 
-	// Code that generates the samples
-	switch noiseType {
-	case White:
-		samples = generateWhiteNoise(samples)
-	case Brown:
-		samples = generateBrownNoise(samples)
-	case Pink:
-		samples = generatePinkNoise(samples)
-	case Minor:
-		samples = generateMinorNoise(samples, numSamples)
+	channels := cfg.Channels
+	if channels < 1 {
+		channels = 1
 	}
 
 	// Initialize portaudio
@@ -118,32 +182,40 @@ func playNoise(duration string, noiseType NoiseType) {
 	}
 	defer portaudio.Terminate()
 
-	// Open default audio stream
-	out := make([]float32, len(samples))
-	stream, err := portaudio.OpenDefaultStream(0, 1, float64(sampleRate), len(samples), &out)
+	// Open a callback stream: PortAudio calls back into Fill whenever it needs
+	// more samples, instead of us generating everything up front. out is
+	// interleaved across channels, but Fill only knows about mono frames, so
+	// generate one sample per frame and duplicate it across channels.
+	var mono []float32
+	callback := func(out []float32) {
+		frames := len(out) / channels
+		if cap(mono) < frames {
+			mono = make([]float32, frames)
+		}
+		mono = mono[:frames]
+		generator.Fill(mono, cfg.SampleRate)
+		for i, s := range mono {
+			for c := 0; c < channels; c++ {
+				out[i*channels+c] = s
+			}
+		}
+	}
+
+	stream, err := openOutputStream(cfg, callback)
 	if err != nil {
-		fmt.Printf("Could not open default stream: %v", err)
+		fmt.Printf("Could not open output stream: %v", err)
 		os.Exit(1)
 	}
 	defer stream.Close()
 
-	stream.Start()
+	if err := stream.Start(); err != nil {
+		fmt.Printf("Could not start stream: %v", err)
+		os.Exit(1)
+	}
 	defer stream.Stop()
 
 	stop := make(chan bool)
 
-	go func() {
-		for {
-			select {
-			case <-stop:
-				return
-			default:
-				copy(out, samples)
-				stream.Write()
-			}
-		}
-	}()
-
 	// If a duration has been set, send a stop signal after that many minutes
 	if duration != "" {
 		parsedDuration, err := time.ParseDuration(duration)
@@ -157,66 +229,12 @@ func playNoise(duration string, noiseType NoiseType) {
 		}()
 	}
 
-	// Exit immediately when receiving an interrupt signal
-	<-signalChannel
-	os.Exit(0)
-}
-
-func generateWhiteNoise(samples []float32) []float32 {
-	for i := range samples {
-		randomValue := rand.Float64()*2 - 1 // between -1 and 1
-		samples[i] = float32(randomValue)
-	}
-	return samples
-}
-
-func generateBrownNoise(samples []float32) []float32 {
-	var lastValue float64
-	for i := range samples {
-		randomValue := rand.NormFloat64()
-		currentValue := (lastValue + (0.02 * randomValue)) / 1.02
-		samples[i] = float32(currentValue)
-		lastValue = currentValue
-	}
-	return samples
-}
-
-func generatePinkNoise(samples []float32) []float32 {
-	b := [16]float64{}
-	for i := range b {
-		b[i] = rand.Float64()*2 - 1 // Random value between -1 and 1
-	}
-	for i := range samples {
-		k := rand.Intn(len(b))
-		output := b[k]
-		b[k] = rand.Float64()*2 - 1 // Random value between -1 and 1
-		output -= b[k]
-		samples[i] = float32(output)
+	// Exit as soon as either the duration elapses or we receive an interrupt
+	select {
+	case <-stop:
+	case <-signalChannel:
 	}
-	return samples
-}
-
-func generateMinorNoise(samples []float32, numSamples int) []float32 {
-	notes := make([]float64, 16)
-	for i := range notes {
-		octave := 2 + rand.Intn(3) // 2nd tof 4th octave
-		step := rand.Intn(7)       // step within the octave
-		notes[i] = 440 * math.Pow(2, float64(octave+step)/12)
-	}
-
-	noteSamples := noteLength * sampleRate
-
-	for i := 0; i < numSamples-noteSamples; i += noteSamples {
-		omega := notes[rand.Intn(len(notes))] * 2 * math.Pi / float64(sampleRate)
-
-		for j := 0; j < noteSamples; j++ {
-			vibrato := 1 + vibratoDepth*math.Sin(2*math.Pi*vibratoRate*float64(j)/float64(sampleRate)) // Add a vibrato effect
-			t := float64(j) / float64(noteSamples)
-			amplitude := math.Sin(t * math.Pi) // Fade in and out over the duration of the note
-			samples[i+j] = float32(amplitude * vibrato * math.Sin(float64(j)*omega))
-		}
-	}
-	return samples
+	os.Exit(0)
 }
 
 func listDevices() {
@@ -232,7 +250,10 @@ func listDevices() {
 }
 
 func printHelp() {
-	fmt.Println("play: Play noise. Options: --duration [int], --type [white, brown, pink]")
+	fmt.Println("play: Play noise. Options: --duration [string], --type [white, brown, pink], --pink-rows [int], --brown-leak [float], --device [id], --sample-rate [int], --channels [int], --latency [low, high], --preset [name]")
 	fmt.Println("list: List audio devices. No options.")
+	fmt.Println("visualize: Show a live FFT spectrum of the default input device. No options.")
+	fmt.Println("render: Render noise to a file. Options: --out [path], --format [wav, flac], --duration [string], --type [white, brown, pink, minor], --sample-rate [int], --bit-depth [16, 24, 32f]")
+	fmt.Println("presets: List presets configured in ~/.config/fuzzpillow/config.toml. No options.")
 	fmt.Println("help: Show this help message.")
 }
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"math"
+	"math/bits"
+	"math/rand"
+)
+
+// WhiteNoiseGenerator fills samples with uniform random values. It carries no
+// state between calls.
+type WhiteNoiseGenerator struct{}
+
+func NewWhiteNoiseGenerator() *WhiteNoiseGenerator {
+	return &WhiteNoiseGenerator{}
+}
+
+func (g *WhiteNoiseGenerator) Fill(out []float32, sampleRate float64) {
+	for i := range out {
+		out[i] = float32(rand.Float64()*2 - 1)
+	}
+}
+
+func (g *WhiteNoiseGenerator) Reset() {}
+
+// brownHighPassR is the pole of the DC-blocking high-pass filter applied
+// after integration; closer to 1 pushes the cutoff lower.
+const brownHighPassR = 0.995
+
+// BrownNoiseGenerator is a leaky integrator of white noise: each sample the
+// integrator decays by the fraction leak before a new white sample is added,
+// so it does not wander off as an unbounded random walk. A one-pole
+// high-pass filter then removes the remaining DC drift, and the output is
+// normalized against the running peak so it stays within [-1, 1].
+type BrownNoiseGenerator struct {
+	leak       float64
+	integrator float64
+	hpPrevIn   float64
+	hpPrevOut  float64
+	peak       float64
+}
+
+func NewBrownNoiseGenerator(leak float64) *BrownNoiseGenerator {
+	return &BrownNoiseGenerator{leak: leak}
+}
+
+func (g *BrownNoiseGenerator) Fill(out []float32, sampleRate float64) {
+	for i := range out {
+		white := rand.Float64()*2 - 1
+		g.integrator = (1-g.leak)*g.integrator + white
+
+		filtered := g.integrator - g.hpPrevIn + brownHighPassR*g.hpPrevOut
+		g.hpPrevIn = g.integrator
+		g.hpPrevOut = filtered
+
+		if abs := math.Abs(filtered); abs > g.peak {
+			g.peak = abs
+		}
+
+		if g.peak > 0 {
+			out[i] = float32(filtered / g.peak)
+		} else {
+			out[i] = 0
+		}
+	}
+}
+
+func (g *BrownNoiseGenerator) Reset() {
+	g.integrator = 0
+	g.hpPrevIn = 0
+	g.hpPrevOut = 0
+	g.peak = 0
+}
+
+// PinkNoiseGenerator implements Voss-McCartney pink noise: it keeps len(rows)
+// white-noise rows where row k is refreshed once every 2^(k+1) samples (found
+// by counting the trailing zeros of the running sample index, with the last
+// row also taking every higher count), maintains a
+// running sum of the rows so each sample is O(1), and adds one row that is
+// refreshed on every sample.
+type PinkNoiseGenerator struct {
+	rows  []float64
+	sum   float64
+	white float64
+	index uint64
+}
+
+func NewPinkNoiseGenerator(rowCount int) *PinkNoiseGenerator {
+	if rowCount < 1 {
+		rowCount = 1
+	}
+	g := &PinkNoiseGenerator{rows: make([]float64, rowCount)}
+	g.Reset()
+	return g
+}
+
+func (g *PinkNoiseGenerator) Fill(out []float32, sampleRate float64) {
+	for i := range out {
+		g.index++
+		row := bits.TrailingZeroCount64(g.index)
+		if row >= len(g.rows) {
+			row = len(g.rows) - 1
+		}
+		newValue := rand.Float64()*2 - 1
+		g.sum += newValue - g.rows[row]
+		g.rows[row] = newValue
+
+		g.white = rand.Float64()*2 - 1
+
+		out[i] = float32((g.sum + g.white) / float64(len(g.rows)+1))
+	}
+}
+
+func (g *PinkNoiseGenerator) Reset() {
+	g.sum = 0
+	for i := range g.rows {
+		g.rows[i] = rand.Float64()*2 - 1
+		g.sum += g.rows[i]
+	}
+	g.white = rand.Float64()*2 - 1
+	g.index = 0
+}
+
+// MinorNoiseGenerator plays a sequence of randomly chosen minor-scale notes,
+// tracking its position within the current note across calls.
+type MinorNoiseGenerator struct {
+	notes        []float64
+	noteSamples  int
+	sampleInNote int
+	omega        float64
+}
+
+func NewMinorNoiseGenerator() *MinorNoiseGenerator {
+	g := &MinorNoiseGenerator{}
+	g.Reset()
+	return g
+}
+
+func (g *MinorNoiseGenerator) Fill(out []float32, sampleRate float64) {
+	if g.noteSamples == 0 {
+		g.noteSamples = int(noteLength * sampleRate)
+	}
+	for i := range out {
+		if g.sampleInNote == 0 {
+			g.omega = g.notes[rand.Intn(len(g.notes))] * 2 * math.Pi / sampleRate
+		}
+		vibrato := 1 + vibratoDepth*math.Sin(2*math.Pi*vibratoRate*float64(g.sampleInNote)/sampleRate)
+		t := float64(g.sampleInNote) / float64(g.noteSamples)
+		amplitude := math.Sin(t * math.Pi)
+		out[i] = float32(amplitude * vibrato * math.Sin(float64(g.sampleInNote)*g.omega))
+
+		g.sampleInNote++
+		if g.sampleInNote >= g.noteSamples {
+			g.sampleInNote = 0
+		}
+	}
+}
+
+func (g *MinorNoiseGenerator) Reset() {
+	notes := make([]float64, 16)
+	for i := range notes {
+		octave := 2 + rand.Intn(3) // 2nd to 4th octave
+		step := rand.Intn(7)       // step within the octave
+		notes[i] = 440 * math.Pow(2, float64(octave+step)/12)
+	}
+	g.notes = notes
+	g.noteSamples = 0
+	g.sampleInNote = 0
+}
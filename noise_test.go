@@ -0,0 +1,11 @@
+package main
+
+import "testing"
+
+func TestNewPinkNoiseGeneratorClampsNonPositiveRowCount(t *testing.T) {
+	for _, rowCount := range []int{0, -1, -16} {
+		g := NewPinkNoiseGenerator(rowCount)
+		out := make([]float32, 8)
+		g.Fill(out, sampleRate) // must not panic
+	}
+}
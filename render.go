@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"time"
+)
+
+// renderToFile generates duration worth of noiseType samples and writes
+// them to path in the requested format, as a natural file-based counterpart
+// to the real-time play command.
+func renderToFile(path, format, duration string, noiseType NoiseType, noiseOpts NoiseOptions, sampleRate float64, bitDepth string) error {
+	parsedDuration, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("could not parse duration: %w", err)
+	}
+
+	numSamples := int(parsedDuration.Seconds() * sampleRate)
+	samples := make([]float32, numSamples)
+	newGenerator(noiseType, noiseOpts).Fill(samples, sampleRate)
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	w := bufio.NewWriter(f)
+	defer w.Flush()
+
+	bits, isFloat, err := parseBitDepth(bitDepth)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case "wav":
+		if err := writeWAVHeader(w, numSamples, 1, int(sampleRate), bits, isFloat); err != nil {
+			return fmt.Errorf("could not write wav header: %w", err)
+		}
+		return writeWAVSamples(w, samples, bits, isFloat)
+	case "flac":
+		if isFloat {
+			return fmt.Errorf("flac does not support bit depth %s (use --format wav)", bitDepth)
+		}
+		return writeFLAC(w, samples, int(sampleRate), bits)
+	default:
+		return fmt.Errorf("invalid format: %s (want wav or flac)", format)
+	}
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+// defaultTerminalWidth is used when the terminal size can't be determined,
+// e.g. when stdout isn't a TTY.
+const defaultTerminalWidth = 80
+
+type winsize struct {
+	Rows, Cols, XPixel, YPixel uint16
+}
+
+// terminalWidth returns the number of columns in the controlling terminal,
+// falling back to defaultTerminalWidth if it can't be queried.
+func terminalWidth() int {
+	ws := &winsize{}
+	ret, _, _ := syscall.Syscall(syscall.SYS_IOCTL,
+		uintptr(syscall.Stdout),
+		uintptr(syscall.TIOCGWINSZ),
+		uintptr(unsafe.Pointer(ws)))
+	if int(ret) == -1 || ws.Cols == 0 {
+		return defaultTerminalWidth
+	}
+	return int(ws.Cols)
+}
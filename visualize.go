@@ -0,0 +1,180 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/gordonklaus/portaudio"
+)
+
+const (
+	visualizeFrameSize = 2048                   // samples per FFT frame, a power of two
+	visualizeHopSize   = visualizeFrameSize / 2 // 50% overlap between frames (Welch's method)
+	visualizeFPS       = 30                     // target render rate
+	visualizeMinDB     = -80.0                  // noise floor; quieter bins render as empty
+	visualizeBarHeight = 40                     // maximum height, in rows, of a single bar
+)
+
+// visualizeSpectrum opens the default input stream and renders a live,
+// dB-scaled FFT magnitude spectrum in the terminal until interrupted. Frames
+// overlap 50% (Welch's method) and are averaged with the previous frame to
+// smooth the display.
+func visualizeSpectrum() {
+	signalChannel := make(chan os.Signal, 1)
+	signal.Notify(signalChannel, syscall.SIGINT, syscall.SIGTERM)
+
+	err := portaudio.Initialize()
+	if err != nil {
+		fmt.Printf("Could not initialize portaudio: %v", err)
+		os.Exit(1)
+	}
+	defer portaudio.Terminate()
+
+	hop := make([]float32, visualizeHopSize)
+	stream, err := portaudio.OpenDefaultStream(1, 0, float64(sampleRate), len(hop), &hop)
+	if err != nil {
+		fmt.Printf("Could not open default stream: %v", err)
+		os.Exit(1)
+	}
+	defer stream.Close()
+
+	if err := stream.Start(); err != nil {
+		fmt.Printf("Could not start stream: %v", err)
+		os.Exit(1)
+	}
+	defer stream.Stop()
+
+	stop := make(chan bool)
+	go func() {
+		<-signalChannel
+		stop <- true
+	}()
+
+	window := hannWindow(visualizeFrameSize)
+	frame := make([]float32, visualizeFrameSize)
+	var prevDB []float64
+	minFrameInterval := time.Second / visualizeFPS
+	var lastRender time.Time
+
+	for {
+		select {
+		case <-stop:
+			return
+		default:
+		}
+
+		if err := stream.Read(); err != nil {
+			fmt.Printf("Could not read from stream: %v", err)
+			return
+		}
+
+		copy(frame, frame[visualizeHopSize:])
+		copy(frame[visualizeHopSize:], hop)
+
+		if time.Since(lastRender) < minFrameInterval {
+			continue
+		}
+		lastRender = time.Now()
+
+		db := spectrumDB(frame, window)
+		if prevDB != nil {
+			for i := range db {
+				db[i] = (db[i] + prevDB[i]) / 2
+			}
+		}
+		prevDB = db
+
+		renderSpectrum(db, terminalWidth())
+	}
+}
+
+// spectrumDB windows frame, runs it through fft, and converts the lower
+// half of the spectrum (the upper half mirrors it for real input) to dB,
+// floored at visualizeMinDB.
+func spectrumDB(frame []float32, window []float64) []float64 {
+	n := len(frame)
+	in := make([]complex128, n)
+	for i, s := range frame {
+		in[i] = complex(float64(s)*window[i], 0)
+	}
+	spectrum := fft(in)
+
+	half := n / 2
+	db := make([]float64, half)
+	for i := 0; i < half; i++ {
+		magnitude := cmplx.Abs(spectrum[i]) / float64(half)
+		level := 20 * math.Log10(magnitude+1e-12)
+		if level < visualizeMinDB {
+			level = visualizeMinDB
+		}
+		db[i] = level
+	}
+	return db
+}
+
+// renderSpectrum bins db into one bar per terminal column and prints the
+// bars side by side in a single visualizeBarHeight-row block, colored from
+// green (quiet) to red (loud).
+func renderSpectrum(db []float64, width int) {
+	bars := width
+	if bars < 1 {
+		bars = 1
+	}
+	binsPerBar := len(db) / bars
+	if binsPerBar < 1 {
+		binsPerBar = 1
+	}
+
+	heights := make([]int, bars)
+	normalized := make([]float64, bars)
+	for i := 0; i < bars; i++ {
+		peak := visualizeMinDB
+		for j := 0; j < binsPerBar; j++ {
+			if idx := i*binsPerBar + j; idx < len(db) && db[idx] > peak {
+				peak = db[idx]
+			}
+		}
+
+		n := (peak - visualizeMinDB) / -visualizeMinDB
+		if n < 0 {
+			n = 0
+		}
+		normalized[i] = n
+		heights[i] = int(n * visualizeBarHeight)
+	}
+
+	var out strings.Builder
+	out.WriteString("\033[H\033[2J") // reset cursor and clear the screen between frames
+	for row := visualizeBarHeight; row >= 1; row-- {
+		for i := 0; i < bars; i++ {
+			if heights[i] >= row {
+				out.WriteString(spectrumColor(normalized[i]))
+				out.WriteString("#")
+				out.WriteString("\033[0m")
+			} else {
+				out.WriteString(" ")
+			}
+		}
+		out.WriteString("\n")
+	}
+	fmt.Print(out.String())
+}
+
+// spectrumColor maps a 0-1 normalized level to an ANSI color, from green
+// (quiet) through yellow to red (loud).
+func spectrumColor(normalized float64) string {
+	switch {
+	case normalized > 0.66:
+		return "\033[31m"
+	case normalized > 0.33:
+		return "\033[33m"
+	default:
+		return "\033[32m"
+	}
+}
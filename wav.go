@@ -0,0 +1,105 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	wavFormatPCM       = 1
+	wavFormatIEEEFloat = 3
+)
+
+// parseBitDepth validates the --bit-depth flag value, returning the number
+// of bits per sample and whether it's an IEEE float format (32f).
+func parseBitDepth(s string) (bits int, isFloat bool, err error) {
+	switch s {
+	case "16":
+		return 16, false, nil
+	case "24":
+		return 24, false, nil
+	case "32f":
+		return 32, true, nil
+	default:
+		return 0, false, fmt.Errorf("invalid bit depth: %s (want 16, 24, or 32f)", s)
+	}
+}
+
+// writeWAVHeader writes a standard RIFF/WAVE header for numSamples
+// interleaved samples, using WAVE_FORMAT_IEEE_FLOAT for 32-bit float output
+// and WAVE_FORMAT_PCM otherwise.
+func writeWAVHeader(w io.Writer, numSamples, channels, sampleRate, bits int, isFloat bool) error {
+	format := uint16(wavFormatPCM)
+	if isFloat {
+		format = wavFormatIEEEFloat
+	}
+
+	bytesPerSample := bits / 8
+	blockAlign := channels * bytesPerSample
+	byteRate := sampleRate * blockAlign
+	dataSize := numSamples * bytesPerSample
+	riffSize := 36 + dataSize
+
+	buf := new(bytes.Buffer)
+	buf.WriteString("RIFF")
+	binary.Write(buf, binary.LittleEndian, uint32(riffSize))
+	buf.WriteString("WAVE")
+	buf.WriteString("fmt ")
+	binary.Write(buf, binary.LittleEndian, uint32(16))
+	binary.Write(buf, binary.LittleEndian, format)
+	binary.Write(buf, binary.LittleEndian, uint16(channels))
+	binary.Write(buf, binary.LittleEndian, uint32(sampleRate))
+	binary.Write(buf, binary.LittleEndian, uint32(byteRate))
+	binary.Write(buf, binary.LittleEndian, uint16(blockAlign))
+	binary.Write(buf, binary.LittleEndian, uint16(bits))
+	buf.WriteString("data")
+	binary.Write(buf, binary.LittleEndian, uint32(dataSize))
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// writeWAVSamples writes samples as interleaved PCM or IEEE float data
+// matching the bits/isFloat combination passed to writeWAVHeader.
+func writeWAVSamples(w io.Writer, samples []float32, bits int, isFloat bool) error {
+	buf := new(bytes.Buffer)
+
+	switch {
+	case isFloat:
+		for _, s := range samples {
+			binary.Write(buf, binary.LittleEndian, s)
+		}
+	case bits == 16:
+		for _, s := range samples {
+			binary.Write(buf, binary.LittleEndian, int16(clampUnit(s)*math.MaxInt16))
+		}
+	case bits == 24:
+		for _, s := range samples {
+			v := int32(clampUnit(s) * (1<<23 - 1))
+			buf.WriteByte(byte(v))
+			buf.WriteByte(byte(v >> 8))
+			buf.WriteByte(byte(v >> 16))
+		}
+	default:
+		return fmt.Errorf("unsupported bit depth: %d", bits)
+	}
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// clampUnit clamps s to [-1, 1] before it's scaled to an integer sample.
+func clampUnit(s float32) float64 {
+	v := float64(s)
+	switch {
+	case v > 1:
+		return 1
+	case v < -1:
+		return -1
+	default:
+		return v
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestWriteWAVHeaderLayout(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeWAVHeader(&buf, 100, 1, 44100, 16, false); err != nil {
+		t.Fatalf("writeWAVHeader: %v", err)
+	}
+
+	b := buf.Bytes()
+	if got := string(b[0:4]); got != "RIFF" {
+		t.Errorf("chunk ID = %q, want RIFF", got)
+	}
+	if got := string(b[8:12]); got != "WAVE" {
+		t.Errorf("format = %q, want WAVE", got)
+	}
+	if got := binary.LittleEndian.Uint16(b[20:22]); got != wavFormatPCM {
+		t.Errorf("audio format = %d, want %d", got, wavFormatPCM)
+	}
+	if got := binary.LittleEndian.Uint32(b[40:44]); got != 200 {
+		t.Errorf("data size = %d, want 200 (100 samples * 2 bytes)", got)
+	}
+}
+
+func TestWriteWAVHeaderUsesIEEEFloatFormat(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeWAVHeader(&buf, 10, 1, 44100, 32, true); err != nil {
+		t.Fatalf("writeWAVHeader: %v", err)
+	}
+
+	if got := binary.LittleEndian.Uint16(buf.Bytes()[20:22]); got != wavFormatIEEEFloat {
+		t.Errorf("audio format = %d, want %d", got, wavFormatIEEEFloat)
+	}
+}
+
+func TestParseBitDepth(t *testing.T) {
+	cases := []struct {
+		in        string
+		wantBits  int
+		wantFloat bool
+	}{
+		{"16", 16, false},
+		{"24", 24, false},
+		{"32f", 32, true},
+	}
+	for _, c := range cases {
+		bits, isFloat, err := parseBitDepth(c.in)
+		if err != nil {
+			t.Fatalf("parseBitDepth(%q): %v", c.in, err)
+		}
+		if bits != c.wantBits || isFloat != c.wantFloat {
+			t.Errorf("parseBitDepth(%q) = (%d, %v), want (%d, %v)", c.in, bits, isFloat, c.wantBits, c.wantFloat)
+		}
+	}
+
+	if _, _, err := parseBitDepth("8"); err == nil {
+		t.Error("parseBitDepth(\"8\") expected an error, got nil")
+	}
+}